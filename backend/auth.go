@@ -0,0 +1,56 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// secureCompare reports whether a and b are equal without leaking timing
+// information about where they first differ.
+func secureCompare(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// requireAuth gates a handler behind HTTP basic auth or a bearer token,
+// whichever is configured. With neither set (the default dev setup) it's a no-op.
+func requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if config.AuthToken == "" && config.AuthUser == "" {
+			next(w, r)
+			return
+		}
+
+		if config.AuthToken != "" {
+			if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && secureCompare(token, config.AuthToken) {
+				next(w, r)
+				return
+			}
+		}
+
+		if config.AuthUser != "" {
+			if user, pass, ok := r.BasicAuth(); ok && secureCompare(user, config.AuthUser) && secureCompare(pass, config.AuthPass) {
+				next(w, r)
+				return
+			}
+		}
+
+		w.Header().Set("WWW-Authenticate", `Basic realm="sysdash"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}
+}
+
+// originAllowed checks a WebSocket upgrade's Origin header against the configured
+// allowlist. An empty allowlist preserves the existing dev-mode "allow all" behavior.
+func originAllowed(r *http.Request) bool {
+	if len(config.OriginAllowlist) == 0 {
+		return true
+	}
+	origin := r.Header.Get("Origin")
+	for _, allowed := range config.OriginAllowlist {
+		if origin == allowed {
+			return true
+		}
+	}
+	return false
+}
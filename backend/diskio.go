@@ -0,0 +1,55 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// DiskIOStats is one block device's cumulative and per-second I/O throughput.
+type DiskIOStats struct {
+	Name             string  `json:"name"`
+	ReadBytes        uint64  `json:"readBytes"`
+	WriteBytes       uint64  `json:"writeBytes"`
+	ReadBytesPerSec  float64 `json:"readBytesPerSec"`
+	WriteBytesPerSec float64 `json:"writeBytesPerSec"`
+	ReadOpsPerSec    float64 `json:"readOpsPerSec"`
+	WriteOpsPerSec   float64 `json:"writeOpsPerSec"`
+}
+
+var prevDiskIO map[string]disk.IOCountersStat
+var prevDiskIOTime time.Time
+var diskIOMutex sync.Mutex
+
+// computeDiskIOStats is the disk-I/O analog of updateCPUPercents: it diffs the
+// current disk.IOCounters snapshot against the previous one to derive rates.
+func computeDiskIOStats() []DiskIOStats {
+	counters, err := disk.IOCounters()
+	if err != nil {
+		return nil
+	}
+
+	diskIOMutex.Lock()
+	defer diskIOMutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(prevDiskIOTime).Seconds()
+
+	var out []DiskIOStats
+	for name, c := range counters {
+		stat := DiskIOStats{Name: name, ReadBytes: c.ReadBytes, WriteBytes: c.WriteBytes}
+
+		if prev, ok := prevDiskIO[name]; ok && elapsed > 0 {
+			stat.ReadBytesPerSec = float64(c.ReadBytes-prev.ReadBytes) / elapsed
+			stat.WriteBytesPerSec = float64(c.WriteBytes-prev.WriteBytes) / elapsed
+			stat.ReadOpsPerSec = float64(c.ReadCount-prev.ReadCount) / elapsed
+			stat.WriteOpsPerSec = float64(c.WriteCount-prev.WriteCount) / elapsed
+		}
+		out = append(out, stat)
+	}
+
+	prevDiskIO = counters
+	prevDiskIOTime = now
+	return out
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/client"
+)
+
+// watchContainersRetryDelay is how long watchContainers waits before re-listing
+// and re-subscribing after the event stream drops.
+const watchContainersRetryDelay = 2 * time.Second
+
+// ContainerStats is one running container's resource usage, following the same
+// fields the Docker CLI's `docker stats` surfaces.
+type ContainerStats struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	CPUPercentage float64 `json:"cpuPercentage"`
+	MemUsage      uint64  `json:"memUsage"`
+	MemLimit      uint64  `json:"memLimit"`
+	MemPercentage float64 `json:"memPercentage"`
+	NetworkRx     uint64  `json:"networkRx"`
+	NetworkTx     uint64  `json:"networkTx"`
+	BlockRead     uint64  `json:"blockRead"`
+	BlockWrite    uint64  `json:"blockWrite"`
+}
+
+// containerRegistry tracks one stats-streaming goroutine per running container.
+type containerRegistry struct {
+	mu      sync.RWMutex
+	stats   map[string]ContainerStats
+	cancels map[string]context.CancelFunc
+}
+
+var containers = &containerRegistry{
+	stats:   make(map[string]ContainerStats),
+	cancels: make(map[string]context.CancelFunc),
+}
+
+func (r *containerRegistry) add(ctx context.Context, cli *client.Client, id, name string) {
+	r.mu.Lock()
+	if _, exists := r.cancels[id]; exists {
+		r.mu.Unlock()
+		return
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	r.cancels[id] = cancel
+	r.mu.Unlock()
+
+	go streamContainerStats(streamCtx, cli, id, name)
+}
+
+func (r *containerRegistry) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cancel, ok := r.cancels[id]; ok {
+		cancel()
+		delete(r.cancels, id)
+	}
+	delete(r.stats, id)
+}
+
+func (r *containerRegistry) set(id string, s ContainerStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats[id] = s
+}
+
+func (r *containerRegistry) snapshot() []ContainerStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]ContainerStats, 0, len(r.stats))
+	for _, s := range r.stats {
+		out = append(out, s)
+	}
+	return out
+}
+
+// streamContainerStats decodes the `/containers/{id}/stats` streaming JSON and
+// computes CPUPercentage the same way the Docker CLI's stats_helpers.go does:
+// (cpuDelta / systemDelta) * onlineCPUs * 100.
+func streamContainerStats(ctx context.Context, cli *client.Client, id, name string) {
+	resp, err := cli.ContainerStats(ctx, id, true)
+	if err != nil {
+		log.Printf("Error streaming stats for container %s: %v", name, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var v container.StatsResponse
+		if err := decoder.Decode(&v); err != nil {
+			if err != io.EOF {
+				log.Printf("Error decoding stats for container %s: %v", name, err)
+			}
+			containers.remove(id)
+			return
+		}
+
+		cpuPercent := calculateCPUPercentage(&v)
+		memPercent := 0.0
+		if v.MemoryStats.Limit > 0 {
+			memPercent = float64(v.MemoryStats.Usage) / float64(v.MemoryStats.Limit) * 100
+		}
+
+		var rx, tx uint64
+		for _, n := range v.Networks {
+			rx += n.RxBytes
+			tx += n.TxBytes
+		}
+
+		var blkRead, blkWrite uint64
+		for _, b := range v.BlkioStats.IoServiceBytesRecursive {
+			switch b.Op {
+			case "Read", "read":
+				blkRead += b.Value
+			case "Write", "write":
+				blkWrite += b.Value
+			}
+		}
+
+		containers.set(id, ContainerStats{
+			ID:            id,
+			Name:          name,
+			CPUPercentage: cpuPercent,
+			MemUsage:      v.MemoryStats.Usage,
+			MemLimit:      v.MemoryStats.Limit,
+			MemPercentage: memPercent,
+			NetworkRx:     rx,
+			NetworkTx:     tx,
+			BlockRead:     blkRead,
+			BlockWrite:    blkWrite,
+		})
+	}
+}
+
+func calculateCPUPercentage(v *container.StatsResponse) float64 {
+	cpuDelta := float64(v.CPUStats.CPUUsage.TotalUsage) - float64(v.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(v.CPUStats.SystemUsage) - float64(v.PreCPUStats.SystemUsage)
+	onlineCPUs := float64(v.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(v.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if systemDelta > 0 && cpuDelta > 0 {
+		return (cpuDelta / systemDelta) * onlineCPUs * 100
+	}
+	return 0
+}
+
+// watchContainers reacts to container create/destroy events to keep the
+// registry's streaming goroutines in sync with what's actually running. The
+// event stream can drop (daemon restart, network blip); rather than exiting
+// and freezing stats forever, it re-lists and re-subscribes after a delay.
+func watchContainers(ctx context.Context, cli *client.Client) {
+	for {
+		if err := watchContainersOnce(ctx, cli); err != nil {
+			log.Printf("Docker event stream error, reconnecting in %s: %v", watchContainersRetryDelay, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchContainersRetryDelay):
+		}
+	}
+}
+
+// watchContainersOnce lists currently running containers, then subscribes to
+// the event stream until it errors, closes, or ctx is cancelled.
+func watchContainersOnce(ctx context.Context, cli *client.Client) error {
+	list, err := cli.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, c := range list {
+		name := c.ID
+		if len(c.Names) > 0 {
+			name = c.Names[0]
+		}
+		containers.add(ctx, cli, c.ID, name)
+	}
+
+	msgs, errs := cli.Events(ctx, events.ListOptions{})
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case msg := <-msgs:
+			if msg.Type != events.ContainerEventType {
+				continue
+			}
+			switch msg.Action {
+			case events.ActionStart:
+				containers.add(ctx, cli, msg.Actor.ID, msg.Actor.Attributes["name"])
+			case events.ActionDie, events.ActionStop, events.ActionDestroy:
+				containers.remove(msg.Actor.ID)
+			}
+		}
+	}
+}
+
+// initDocker connects to the Docker socket (respecting DOCKER_HOST) and starts
+// watching containers if it's reachable. It's a no-op if Docker isn't available.
+func initDocker() {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		log.Printf("Docker integration disabled: %v", err)
+		return
+	}
+
+	ctx := context.Background()
+	if _, err := cli.Ping(ctx); err != nil {
+		log.Printf("Docker integration disabled: %v", err)
+		return
+	}
+
+	log.Printf("Docker integration enabled")
+	go watchContainers(ctx, cli)
+}
+
+func getContainerStats() []ContainerStats {
+	return containers.snapshot()
+}
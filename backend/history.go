@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HistoryWindow is how far back the ring buffer retains samples.
+const HistoryWindow = time.Hour
+
+// HistoryPoint is one timestamped sample of a single metric.
+type HistoryPoint struct {
+	Timestamp int64   `json:"timestamp"` // unix seconds
+	Value     float64 `json:"value"`
+}
+
+// historyEntry pairs a snapshot with the time it was collected.
+type historyEntry struct {
+	timestamp int64
+	stats     *Stats
+}
+
+var historyBuf []historyEntry
+var historyCap int
+var historyMutex sync.RWMutex
+
+// initHistory sizes the ring buffer from the configured update interval so it
+// always covers HistoryWindow regardless of how fast the collector ticks.
+func initHistory() {
+	historyCap = int(HistoryWindow / config.UpdateInterval)
+	if historyCap < 1 {
+		historyCap = 1
+	}
+	historyBuf = make([]historyEntry, 0, historyCap)
+}
+
+// appendHistory records a snapshot, evicting the oldest once the buffer is full.
+func appendHistory(s *Stats) {
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	historyBuf = append(historyBuf, historyEntry{timestamp: time.Now().Unix(), stats: s})
+	if len(historyBuf) > historyCap {
+		historyBuf = historyBuf[len(historyBuf)-historyCap:]
+	}
+}
+
+// extractMetric reduces a Stats snapshot to a single float for the requested metric name.
+func extractMetric(s *Stats, metric string) (float64, bool) {
+	switch metric {
+	case "cpu":
+		if len(s.CPU.Percent) == 0 {
+			return 0, false
+		}
+		var sum float64
+		for _, p := range s.CPU.Percent {
+			sum += p
+		}
+		return sum / float64(len(s.CPU.Percent)), true
+	case "memory":
+		return s.Memory.UsedPercent, true
+	case "disk":
+		return s.Disk.UsedPercent, true
+	case "network":
+		var total float64
+		for _, n := range s.Network {
+			total += float64(n.BytesSent + n.BytesRecv)
+		}
+		return total, true
+	case "gpu":
+		if len(s.GPU) == 0 {
+			return 0, false
+		}
+		var sum float64
+		for _, g := range s.GPU {
+			sum += g.UsedPercent
+		}
+		return sum / float64(len(s.GPU)), true
+	default:
+		return 0, false
+	}
+}
+
+// handleHistory serves /api/history?metric=cpu&since=<unix-seconds>.
+func handleHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	metric := r.URL.Query().Get("metric")
+	if metric == "" {
+		http.Error(w, "metric query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	var since int64
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			http.Error(w, "since must be a unix timestamp in seconds", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	historyMutex.RLock()
+	snapshot := make([]historyEntry, len(historyBuf))
+	copy(snapshot, historyBuf)
+	historyMutex.RUnlock()
+
+	points := make([]HistoryPoint, 0, len(snapshot))
+	for _, entry := range snapshot {
+		if since > 0 && entry.timestamp < since {
+			continue
+		}
+		value, ok := extractMetric(entry.stats, metric)
+		if !ok {
+			continue
+		}
+		points = append(points, HistoryPoint{Timestamp: entry.timestamp, Value: value})
+	}
+
+	json.NewEncoder(w).Encode(points)
+}
@@ -29,10 +29,18 @@ var staticFiles embed.FS
 
 // Config holds all configuration options
 type Config struct {
-	Port           string
-	DiskPath       string
-	UpdateInterval time.Duration
-	Hostname       string
+	Port            string
+	DiskPath        string
+	UpdateInterval  time.Duration
+	Hostname        string
+	RemoteMode      bool
+	TLSCertFile     string
+	TLSKeyFile      string
+	AuthUser        string
+	AuthPass        string
+	AuthToken       string
+	OriginAllowlist []string
+	MinWSIntervalMs int
 }
 
 var config Config
@@ -45,15 +53,29 @@ var cpuPercents []float64
 var cpuMutex sync.RWMutex
 
 var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins for dev
-	},
+	CheckOrigin: originAllowed,
 }
 
 func loadConfig() {
 	config.Port = getEnv("PORT", "8080")
 	config.DiskPath = getEnv("DISK_PATH", "/")
 	config.Hostname = getEnv("HOSTNAME", "")
+	config.RemoteMode = getEnv("REMOTE_MODE", "") != ""
+	config.TLSCertFile = getEnv("TLS_CERT_FILE", "")
+	config.TLSKeyFile = getEnv("TLS_KEY_FILE", "")
+	config.AuthUser = getEnv("AUTH_USER", "")
+	config.AuthPass = getEnv("AUTH_PASS", "")
+	config.AuthToken = getEnv("AUTH_TOKEN", "")
+
+	if raw := getEnv("ORIGIN_ALLOWLIST", ""); raw != "" {
+		config.OriginAllowlist = strings.Split(raw, ",")
+	}
+
+	minIntervalMs, _ := strconv.Atoi(getEnv("MIN_WS_INTERVAL_MS", "100"))
+	if minIntervalMs < 50 {
+		minIntervalMs = 50
+	}
+	config.MinWSIntervalMs = minIntervalMs
 
 	intervalMs, _ := strconv.Atoi(getEnv("UPDATE_INTERVAL_MS", "500"))
 	if intervalMs < 100 {
@@ -123,6 +145,10 @@ func collectStats() {
 			statsMutex.Lock()
 			cachedStats = stats
 			statsMutex.Unlock()
+
+			appendHistory(stats)
+			updateMetrics(stats)
+			hub.publish(stats)
 		}
 		time.Sleep(config.UpdateInterval)
 	}
@@ -135,15 +161,21 @@ func getCachedStats() *Stats {
 }
 
 type Stats struct {
-	Hostname string     `json:"hostname"`
-	Uptime   uint64     `json:"uptime"`
-	OS       string     `json:"os"`
-	Arch     string     `json:"arch"`
-	CPU      CPUStats   `json:"cpu"`
-	Memory   MemStats   `json:"memory"`
-	Disk     DiskStats  `json:"disk"`
-	Network  []NetStats `json:"network"`
-	GPU      *GPUStats  `json:"gpu,omitempty"`
+	Hostname   string           `json:"hostname"`
+	Uptime     uint64           `json:"uptime"`
+	OS         string           `json:"os"`
+	Arch       string           `json:"arch"`
+	CPU        CPUStats         `json:"cpu"`
+	Memory     MemStats         `json:"memory"`
+	Swap       SwapStats        `json:"swap"`
+	Disk       DiskStats        `json:"disk"`
+	DiskIO     []DiskIOStats    `json:"diskIO,omitempty"`
+	Network    []NetStats       `json:"network"`
+	GPU        []GPUStats       `json:"gpu,omitempty"`
+	Containers []ContainerStats `json:"containers,omitempty"`
+	Load       LoadStats        `json:"load"`
+	Temps      []TempSensor     `json:"temps,omitempty"`
+	Processes  []ProcessInfo    `json:"processes,omitempty"`
 }
 
 type CPUStats struct {
@@ -169,9 +201,55 @@ type DiskStats struct {
 }
 
 type NetStats struct {
-	Name      string `json:"name"`
-	BytesSent uint64 `json:"bytesSent"`
-	BytesRecv uint64 `json:"bytesRecv"`
+	Name            string  `json:"name"`
+	BytesSent       uint64  `json:"bytesSent"`
+	BytesRecv       uint64  `json:"bytesRecv"`
+	BytesSentPerSec float64 `json:"bytesSentPerSec"`
+	BytesRecvPerSec float64 `json:"bytesRecvPerSec"`
+}
+
+var prevNetIO map[string]net.IOCountersStat
+var prevNetIOTime time.Time
+var netIOMutex sync.Mutex
+
+// computeNetStats is the network analog of computeDiskIOStats: it diffs the
+// current net.IOCounters snapshot against the previous one to derive rates.
+func computeNetStats() []NetStats {
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return nil
+	}
+
+	netIOMutex.Lock()
+	defer netIOMutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(prevNetIOTime).Seconds()
+
+	prevByName := make(map[string]net.IOCountersStat, len(prevNetIO))
+	for name, c := range prevNetIO {
+		prevByName[name] = c
+	}
+
+	var out []NetStats
+	current := make(map[string]net.IOCountersStat, len(counters))
+	for _, n := range counters {
+		current[n.Name] = n
+		if n.BytesSent == 0 && n.BytesRecv == 0 {
+			continue
+		}
+
+		stat := NetStats{Name: n.Name, BytesSent: n.BytesSent, BytesRecv: n.BytesRecv}
+		if prev, ok := prevByName[n.Name]; ok && elapsed > 0 {
+			stat.BytesSentPerSec = float64(n.BytesSent-prev.BytesSent) / elapsed
+			stat.BytesRecvPerSec = float64(n.BytesRecv-prev.BytesRecv) / elapsed
+		}
+		out = append(out, stat)
+	}
+
+	prevNetIO = current
+	prevNetIOTime = now
+	return out
 }
 
 type GPUStats struct {
@@ -182,86 +260,94 @@ type GPUStats struct {
 	Temperature float64 `json:"temperature"`
 }
 
-// getGPUStats tries AMD (rocm-smi) then NVIDIA (nvidia-smi)
-func getGPUStats() *GPUStats {
-	// Try AMD first (Linux)
+// getGPUStatsAll enumerates every adapter it can find: AMD (sysfs/rocm-smi), NVIDIA
+// (nvidia-smi), and on Windows every Win32_VideoController.
+func getGPUStatsAll() []GPUStats {
+	var gpus []GPUStats
+
+	// AMD (Linux)
 	if runtime.GOOS == "linux" {
-		if gpu := getAMDGPU(); gpu != nil {
-			return gpu
-		}
+		gpus = append(gpus, getAMDGPUs()...)
 	}
-	// Try NVIDIA (cross-platform)
-	if gpu := getNVIDIAGPU(); gpu != nil {
-		return gpu
-	}
-	// Try Windows WMI for any GPU
+	// NVIDIA (cross-platform)
+	gpus = append(gpus, getNVIDIAGPUs()...)
+	// Windows WMI covers whatever wasn't already picked up above
 	if runtime.GOOS == "windows" {
-		if gpu := getWindowsGPU(); gpu != nil {
-			return gpu
-		}
+		gpus = append(gpus, getWindowsGPUs()...)
 	}
-	return nil
+
+	return gpus
 }
 
-func getAMDGPU() *GPUStats {
+func getAMDGPUs() []GPUStats {
 	// Try rocm-smi first
 	cmd := exec.Command("rocm-smi", "--showmeminfo", "vram", "--showtemp", "--showuse", "--showproductname")
 	output, err := cmd.Output()
 	if err == nil {
-		return parseRocmSMI(string(output))
+		if gpus := parseRocmSMI(string(output)); len(gpus) > 0 {
+			return gpus
+		}
 	}
 
-	// Fallback: try reading from sysfs (works without rocm-smi)
+	// Fallback: walk sysfs directly (works without rocm-smi, and finds every card)
 	return getAMDFromSysfs()
 }
 
-func parseRocmSMI(output string) *GPUStats {
-	gpu := &GPUStats{}
-	scanner := bufio.NewScanner(strings.NewReader(output))
+// parseRocmSMI parses `rocm-smi` output, which prefixes every metric line with
+// "GPU[n]" so a single card's fields can be spread across the output.
+func parseRocmSMI(output string) []GPUStats {
+	byIndex := map[string]*GPUStats{}
+	var order []string
 
+	scanner := bufio.NewScanner(strings.NewReader(output))
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 
-		if strings.Contains(line, "Card series:") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				gpu.Name = strings.TrimSpace(parts[1])
-			}
+		if !strings.HasPrefix(line, "GPU[") {
+			continue
 		}
-		if strings.Contains(line, "GPU use (%)") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				val := strings.TrimSpace(strings.TrimSuffix(parts[1], "%"))
-				gpu.UsedPercent, _ = strconv.ParseFloat(val, 64)
-			}
+		idxEnd := strings.Index(line, "]")
+		if idxEnd < 0 {
+			continue
 		}
-		if strings.Contains(line, "Temperature") && strings.Contains(line, "edge") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				val := strings.TrimSpace(strings.TrimSuffix(parts[1], "c"))
-				gpu.Temperature, _ = strconv.ParseFloat(val, 64)
-			}
+		index := line[4:idxEnd]
+		rest := line[idxEnd+1:]
+
+		parts := strings.SplitN(rest, ":", 2)
+		if len(parts) != 2 {
+			continue
 		}
-		if strings.Contains(line, "VRAM Total Memory") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				val := strings.TrimSpace(parts[1])
-				gpu.MemoryTotal = parseMemoryValue(val)
-			}
+		key := strings.TrimSpace(parts[0])
+		val := strings.TrimSpace(parts[1])
+
+		gpu, ok := byIndex[index]
+		if !ok {
+			gpu = &GPUStats{}
+			byIndex[index] = gpu
+			order = append(order, index)
 		}
-		if strings.Contains(line, "VRAM Total Used Memory") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				val := strings.TrimSpace(parts[1])
-				gpu.MemoryUsed = parseMemoryValue(val)
-			}
+
+		switch {
+		case strings.Contains(key, "Card series"):
+			gpu.Name = val
+		case strings.Contains(key, "GPU use (%)"):
+			gpu.UsedPercent, _ = strconv.ParseFloat(strings.TrimSuffix(val, "%"), 64)
+		case strings.Contains(key, "Temperature") && strings.Contains(key, "edge"):
+			gpu.Temperature, _ = strconv.ParseFloat(strings.TrimSuffix(val, "c"), 64)
+		case strings.Contains(key, "VRAM Total Memory"):
+			gpu.MemoryTotal = parseMemoryValue(val)
+		case strings.Contains(key, "VRAM Total Used Memory"):
+			gpu.MemoryUsed = parseMemoryValue(val)
 		}
 	}
 
-	if gpu.Name == "" {
-		return nil
+	var gpus []GPUStats
+	for _, index := range order {
+		if byIndex[index].Name != "" {
+			gpus = append(gpus, *byIndex[index])
+		}
 	}
-	return gpu
+	return gpus
 }
 
 func parseMemoryValue(s string) uint64 {
@@ -283,13 +369,15 @@ func parseMemoryValue(s string) uint64 {
 	return uint64(val * float64(multiplier))
 }
 
-func getAMDFromSysfs() *GPUStats {
-	// Find AMD GPU in /sys/class/drm
+// getAMDFromSysfs walks every /sys/class/drm/cardN and emits one GPUStats per AMD adapter.
+func getAMDFromSysfs() []GPUStats {
 	dirs, err := os.ReadDir("/sys/class/drm")
 	if err != nil {
 		return nil
 	}
 
+	var gpus []GPUStats
+
 	for _, d := range dirs {
 		if !strings.HasPrefix(d.Name(), "card") || strings.Contains(d.Name(), "-") {
 			continue
@@ -303,7 +391,7 @@ func getAMDFromSysfs() *GPUStats {
 			continue
 		}
 
-		gpu := &GPUStats{}
+		gpu := GPUStats{}
 
 		// Get GPU name from product info
 		if name, err := os.ReadFile(basePath + "/product_name"); err == nil {
@@ -337,63 +425,77 @@ func getAMDFromSysfs() *GPUStats {
 		}
 
 		if gpu.Name != "" {
-			return gpu
+			gpus = append(gpus, gpu)
 		}
 	}
-	return nil
+	return gpus
 }
 
-func getNVIDIAGPU() *GPUStats {
+// getNVIDIAGPUs parses one nvidia-smi output line per installed card.
+func getNVIDIAGPUs() []GPUStats {
 	cmd := exec.Command("nvidia-smi", "--query-gpu=name,memory.total,memory.used,utilization.gpu,temperature.gpu", "--format=csv,noheader,nounits")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil
 	}
 
-	line := strings.TrimSpace(string(output))
-	parts := strings.Split(line, ", ")
-	if len(parts) < 5 {
-		return nil
-	}
-
-	memTotal, _ := strconv.ParseUint(parts[1], 10, 64)
-	memUsed, _ := strconv.ParseUint(parts[2], 10, 64)
-	usage, _ := strconv.ParseFloat(parts[3], 64)
-	temp, _ := strconv.ParseFloat(parts[4], 64)
+	var gpus []GPUStats
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, ", ")
+		if len(parts) < 5 {
+			continue
+		}
 
-	return &GPUStats{
-		Name:        parts[0],
-		MemoryTotal: memTotal * 1024 * 1024, // Convert MiB to bytes
-		MemoryUsed:  memUsed * 1024 * 1024,
-		UsedPercent: usage,
-		Temperature: temp,
+		memTotal, _ := strconv.ParseUint(parts[1], 10, 64)
+		memUsed, _ := strconv.ParseUint(parts[2], 10, 64)
+		usage, _ := strconv.ParseFloat(parts[3], 64)
+		temp, _ := strconv.ParseFloat(parts[4], 64)
+
+		gpus = append(gpus, GPUStats{
+			Name:        parts[0],
+			MemoryTotal: memTotal * 1024 * 1024, // Convert MiB to bytes
+			MemoryUsed:  memUsed * 1024 * 1024,
+			UsedPercent: usage,
+			Temperature: temp,
+		})
 	}
+	return gpus
 }
 
-func getWindowsGPU() *GPUStats {
-	// Use PowerShell to query GPU info via WMI - get all GPUs, pick the one with most VRAM (discrete)
+// getWindowsGPUs queries WMI for every Win32_VideoController.
+func getWindowsGPUs() []GPUStats {
 	cmd := exec.Command("powershell", "-Command",
-		"Get-CimInstance Win32_VideoController | Sort-Object -Property AdapterRAM -Descending | Select-Object -First 1 -Property Name,AdapterRAM | ForEach-Object { $_.Name + '|' + $_.AdapterRAM }")
+		"Get-CimInstance Win32_VideoController | Select-Object -Property Name,AdapterRAM | ForEach-Object { $_.Name + '|' + $_.AdapterRAM }")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil
 	}
 
-	line := strings.TrimSpace(string(output))
-	parts := strings.Split(line, "|")
-	if len(parts) < 2 || parts[0] == "" {
-		return nil
-	}
+	var gpus []GPUStats
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		parts := strings.Split(line, "|")
+		if len(parts) < 2 || parts[0] == "" {
+			continue
+		}
 
-	memTotal, _ := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 64)
+		memTotal, _ := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 64)
 
-	return &GPUStats{
-		Name:        strings.TrimSpace(parts[0]),
-		MemoryTotal: memTotal,
-		MemoryUsed:  0, // WMI doesn't provide current usage
-		UsedPercent: 0, // WMI doesn't provide utilization
-		Temperature: 0, // WMI doesn't provide temperature
+		gpus = append(gpus, GPUStats{
+			Name:        strings.TrimSpace(parts[0]),
+			MemoryTotal: memTotal,
+			MemoryUsed:  0, // WMI doesn't provide current usage
+			UsedPercent: 0, // WMI doesn't provide utilization
+			Temperature: 0, // WMI doesn't provide temperature
+		})
 	}
+	return gpus
 }
 
 // Static info cached at startup
@@ -429,10 +531,11 @@ func getStatsNonBlocking() (*Stats, error) {
 	var memInfo *mem.VirtualMemoryStat
 	var diskInfo *disk.UsageStat
 	var netStats []NetStats
-	var gpuStats *GPUStats
+	var gpuStats []GPUStats
+	var diskIOStats []DiskIOStats
 	var uptime uint64
 
-	wg.Add(5)
+	wg.Add(6)
 
 	go func() {
 		defer wg.Done()
@@ -446,21 +549,17 @@ func getStatsNonBlocking() (*Stats, error) {
 
 	go func() {
 		defer wg.Done()
-		netInfo, _ := net.IOCounters(true)
-		for _, n := range netInfo {
-			if n.BytesSent > 0 || n.BytesRecv > 0 {
-				netStats = append(netStats, NetStats{
-					Name:      n.Name,
-					BytesSent: n.BytesSent,
-					BytesRecv: n.BytesRecv,
-				})
-			}
-		}
+		netStats = computeNetStats()
+	}()
+
+	go func() {
+		defer wg.Done()
+		diskIOStats = computeDiskIOStats()
 	}()
 
 	go func() {
 		defer wg.Done()
-		gpuStats = getGPUStats()
+		gpuStats = getGPUStatsAll()
 	}()
 
 	go func() {
@@ -501,8 +600,14 @@ func getStatsNonBlocking() (*Stats, error) {
 			Free:        diskInfo.Free,
 			UsedPercent: diskInfo.UsedPercent,
 		},
-		Network: netStats,
-		GPU:     gpuStats,
+		Swap:       getSwapStats(),
+		DiskIO:     diskIOStats,
+		Network:    netStats,
+		GPU:        gpuStats,
+		Containers: getContainerStats(),
+		Load:       getLoadStats(),
+		Temps:      getTempSensors(),
+		Processes:  getCachedProcesses(),
 	}, nil
 }
 
@@ -519,6 +624,8 @@ func handleStats(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(stats)
 }
 
+// handleWebSocket subscribes to the stats hub and pushes samples out at the
+// rate the client asks for via ?interval_ms=, clamped to config.MinWSIntervalMs.
 func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -527,38 +634,67 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 	defer conn.Close()
 
+	interval := config.UpdateInterval
+	if raw := r.URL.Query().Get("interval_ms"); raw != "" {
+		if ms, err := strconv.Atoi(raw); err == nil {
+			if ms < config.MinWSIntervalMs {
+				ms = config.MinWSIntervalMs
+			}
+			interval = time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	sub := hub.subscribe()
+	defer hub.unsubscribe(sub)
+
 	log.Printf("Client connected")
 
-	for {
-		stats := getCachedStats()
-		if stats == nil {
-			time.Sleep(100 * time.Millisecond)
-			continue
-		}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-		if err := conn.WriteJSON(stats); err != nil {
-			log.Printf("WebSocket write failed: %v", err)
-			break
+	var latest *Stats
+	for {
+		select {
+		case s := <-sub:
+			latest = s
+		case <-ticker.C:
+			if latest == nil {
+				continue
+			}
+			if err := conn.WriteJSON(latest); err != nil {
+				log.Printf("WebSocket write failed: %v", err)
+				log.Printf("Client disconnected")
+				return
+			}
 		}
-
-		time.Sleep(config.UpdateInterval)
 	}
-
-	log.Printf("Client disconnected")
 }
 
 func main() {
 	loadConfig()
 	initStaticInfo()
+	initHistory()
+	initDocker()
 
 	log.Printf("Config: port=%s disk=%s interval=%s", config.Port, config.DiskPath, config.UpdateInterval)
 
 	// Start background stats collector
 	go collectStats()
+	go collectProcesses()
+
+	// Optional remote aggregator: poll peer sysdash instances into a fleet view
+	if config.RemoteMode {
+		loadPeers()
+		go collectFleet()
+		http.HandleFunc("/api/fleet", requireAuth(handleFleet))
+		http.HandleFunc("/api/fleet/ws", requireAuth(handleFleetWebSocket))
+	}
 
 	// API routes
-	http.HandleFunc("/api/stats", handleStats)
-	http.HandleFunc("/api/ws", handleWebSocket)
+	http.HandleFunc("/api/stats", requireAuth(handleStats))
+	http.HandleFunc("/api/ws", requireAuth(handleWebSocket))
+	http.HandleFunc("/api/history", requireAuth(handleHistory))
+	http.HandleFunc("/metrics", requireAuth(metricsHandler.ServeHTTP))
 
 	// Serve static files from embedded filesystem
 	staticFS, err := fs.Sub(staticFiles, "static")
@@ -568,5 +704,9 @@ func main() {
 	http.Handle("/", http.FileServer(http.FS(staticFS)))
 
 	log.Printf("Starting server on :%s", config.Port)
-	log.Fatal(http.ListenAndServe(":"+config.Port, nil))
+	if config.TLSCertFile != "" && config.TLSKeyFile != "" {
+		log.Fatal(http.ListenAndServeTLS(":"+config.Port, config.TLSCertFile, config.TLSKeyFile, nil))
+	} else {
+		log.Fatal(http.ListenAndServe(":"+config.Port, nil))
+	}
 }
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	metricCPUPercent = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sysdash_cpu_percent",
+		Help: "Per-core CPU utilization percentage",
+	}, []string{"core"})
+
+	metricMemoryUsedPercent = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "sysdash_memory_used_percent",
+		Help: "Memory utilization percentage",
+	})
+
+	metricDiskUsedPercent = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "sysdash_disk_used_percent",
+		Help: "Disk utilization percentage for the configured disk path",
+	})
+
+	metricNetworkBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sysdash_network_bytes_total",
+		Help: "Cumulative bytes sent/received per interface",
+	}, []string{"interface", "direction"})
+
+	metricGPUUsedPercent = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sysdash_gpu_used_percent",
+		Help: "GPU utilization percentage",
+	}, []string{"gpu"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		metricCPUPercent,
+		metricMemoryUsedPercent,
+		metricDiskUsedPercent,
+		metricNetworkBytes,
+		metricGPUUsedPercent,
+	)
+}
+
+// updateMetrics mirrors a freshly collected Stats snapshot into the Prometheus gauges.
+func updateMetrics(s *Stats) {
+	for i, p := range s.CPU.Percent {
+		metricCPUPercent.WithLabelValues(strconv.Itoa(i)).Set(p)
+	}
+	metricMemoryUsedPercent.Set(s.Memory.UsedPercent)
+	metricDiskUsedPercent.Set(s.Disk.UsedPercent)
+	for _, n := range s.Network {
+		metricNetworkBytes.WithLabelValues(n.Name, "sent").Set(float64(n.BytesSent))
+		metricNetworkBytes.WithLabelValues(n.Name, "recv").Set(float64(n.BytesRecv))
+	}
+	for _, g := range s.GPU {
+		metricGPUUsedPercent.WithLabelValues(g.Name).Set(g.UsedPercent)
+	}
+}
+
+var metricsHandler = promhttp.Handler()
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	gopsprocess "github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcessInfo is one row in the top-N process list.
+type ProcessInfo struct {
+	Name       string  `json:"name"`
+	PID        int32   `json:"pid"`
+	CPUPercent float64 `json:"cpuPercent"`
+	RSS        uint64  `json:"rss"`
+}
+
+// TopProcessCount is how many processes are kept, sorted by CPU then RSS.
+const TopProcessCount = 10
+
+// processInterval is slower than the default UpdateInterval because
+// enumerating every process is comparatively expensive.
+const processInterval = 2 * time.Second
+
+var cachedProcesses []ProcessInfo
+var processMutex sync.RWMutex
+
+// trackedProcesses keeps the same *gopsprocess.Process around across ticks so
+// p.Percent(0) diffs CPU time against this process's own previous sample
+// instead of its lifetime average since process start.
+var trackedProcesses = make(map[int32]*gopsprocess.Process)
+
+// collectProcesses runs on its own cadence, independent of collectStats, and
+// merges into whatever snapshot getStatsNonBlocking builds next.
+func collectProcesses() {
+	for {
+		procs, err := listTopProcesses()
+		if err == nil {
+			processMutex.Lock()
+			cachedProcesses = procs
+			processMutex.Unlock()
+		}
+		time.Sleep(processInterval)
+	}
+}
+
+func listTopProcesses() ([]ProcessInfo, error) {
+	pids, err := gopsprocess.Pids()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int32]bool, len(pids))
+	infos := make([]ProcessInfo, 0, len(pids))
+
+	for _, pid := range pids {
+		seen[pid] = true
+
+		p, ok := trackedProcesses[pid]
+		if !ok {
+			p, err = gopsprocess.NewProcess(pid)
+			if err != nil {
+				continue
+			}
+			trackedProcesses[pid] = p
+		}
+
+		name, err := p.Name()
+		if err != nil {
+			delete(trackedProcesses, pid)
+			continue
+		}
+
+		// interval=0 diffs against this same Process's last sample, giving a
+		// point-in-time rate rather than gopsutil's lifetime-average CPUPercent().
+		cpuPercent, _ := p.Percent(0)
+		memInfo, err := p.MemoryInfo()
+		var rss uint64
+		if err == nil && memInfo != nil {
+			rss = memInfo.RSS
+		}
+
+		infos = append(infos, ProcessInfo{
+			Name:       name,
+			PID:        pid,
+			CPUPercent: cpuPercent,
+			RSS:        rss,
+		})
+	}
+
+	// Drop trackers for processes that have exited so the map doesn't grow unbounded.
+	for pid := range trackedProcesses {
+		if !seen[pid] {
+			delete(trackedProcesses, pid)
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].CPUPercent != infos[j].CPUPercent {
+			return infos[i].CPUPercent > infos[j].CPUPercent
+		}
+		return infos[i].RSS > infos[j].RSS
+	})
+
+	if len(infos) > TopProcessCount {
+		infos = infos[:TopProcessCount]
+	}
+	return infos, nil
+}
+
+func getCachedProcesses() []ProcessInfo {
+	processMutex.RLock()
+	defer processMutex.RUnlock()
+	result := make([]ProcessInfo, len(cachedProcesses))
+	copy(result, cachedProcesses)
+	return result
+}
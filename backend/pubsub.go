@@ -0,0 +1,94 @@
+package main
+
+import "sync"
+
+// statsHub fans each newly collected Stats out to subscribed WebSocket clients.
+// Subscribers get a buffered channel of size 1 holding only the latest sample;
+// a slow consumer has its stale value dropped rather than blocking the publisher.
+type statsHub struct {
+	mu   sync.Mutex
+	subs map[chan *Stats]struct{}
+}
+
+var hub = &statsHub{subs: make(map[chan *Stats]struct{})}
+
+func (h *statsHub) subscribe() chan *Stats {
+	ch := make(chan *Stats, 1)
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *statsHub) unsubscribe(ch chan *Stats) {
+	h.mu.Lock()
+	delete(h.subs, ch)
+	h.mu.Unlock()
+	close(ch)
+}
+
+func (h *statsHub) publish(s *Stats) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs {
+		select {
+		case ch <- s:
+		default:
+			// Drop the stale sample and replace it with the new one.
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- s:
+			default:
+			}
+		}
+	}
+}
+
+// fleetHub is the fleet-view analogue of statsHub, fanning each newly
+// collected FleetStats out to subscribed WebSocket clients.
+type fleetHub struct {
+	mu   sync.Mutex
+	subs map[chan *FleetStats]struct{}
+}
+
+var fHub = &fleetHub{subs: make(map[chan *FleetStats]struct{})}
+
+func (h *fleetHub) subscribe() chan *FleetStats {
+	ch := make(chan *FleetStats, 1)
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *fleetHub) unsubscribe(ch chan *FleetStats) {
+	h.mu.Lock()
+	delete(h.subs, ch)
+	h.mu.Unlock()
+	close(ch)
+}
+
+func (h *fleetHub) publish(f *FleetStats) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs {
+		select {
+		case ch <- f:
+		default:
+			// Drop the stale sample and replace it with the new one.
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- f:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Peer is one remote sysdash instance polled by the aggregator.
+type Peer struct {
+	Name string `yaml:"name" json:"name"`
+	URL  string `yaml:"url" json:"url"`
+}
+
+// PeersConfig is the shape of the YAML file pointed to by PEERS_CONFIG.
+type PeersConfig struct {
+	Peers []Peer `yaml:"peers"`
+}
+
+// PeerStats is a single host's stats plus reachability/latency info for the fleet view.
+type PeerStats struct {
+	Peer      Peer   `json:"peer"`
+	Stats     *Stats `json:"stats,omitempty"`
+	Reachable bool   `json:"reachable"`
+	LatencyMs int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+// FleetStats is the merged view served at /api/fleet.
+type FleetStats struct {
+	Hosts []PeerStats `json:"hosts"`
+}
+
+var peers []Peer
+var cachedFleet *FleetStats
+var fleetMutex sync.RWMutex
+
+var httpClient = &http.Client{Timeout: 3 * time.Second}
+
+// loadPeers reads the peer list from PEERS_CONFIG (YAML) or PEERS (comma-separated name=url pairs).
+func loadPeers() {
+	if path := getEnv("PEERS_CONFIG", ""); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Error reading peers config %s: %v", path, err)
+			return
+		}
+		var cfg PeersConfig
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			log.Printf("Error parsing peers config %s: %v", path, err)
+			return
+		}
+		peers = cfg.Peers
+		return
+	}
+
+	raw := getEnv("PEERS", "")
+	if raw == "" {
+		return
+	}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, url, found := strings.Cut(entry, "=")
+		if !found {
+			name, url = url, name // allow a bare URL with no name
+		}
+		peers = append(peers, Peer{Name: strings.TrimSpace(name), URL: strings.TrimSpace(url)})
+	}
+}
+
+// pollPeer fetches /api/stats from a single peer and measures round-trip latency.
+func pollPeer(p Peer) PeerStats {
+	start := time.Now()
+	resp, err := httpClient.Get(strings.TrimRight(p.URL, "/") + "/api/stats")
+	if err != nil {
+		return PeerStats{Peer: p, Reachable: false, Error: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	latency := time.Since(start).Milliseconds()
+
+	if resp.StatusCode != http.StatusOK {
+		return PeerStats{Peer: p, Reachable: false, LatencyMs: latency, Error: "status " + resp.Status}
+	}
+
+	var s Stats
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return PeerStats{Peer: p, Reachable: false, LatencyMs: latency, Error: err.Error()}
+	}
+
+	return PeerStats{Peer: p, Stats: &s, Reachable: true, LatencyMs: latency}
+}
+
+// collectFleet polls every configured peer on the server's UpdateInterval and caches the merged view.
+func collectFleet() {
+	for {
+		var wg sync.WaitGroup
+		hosts := make([]PeerStats, len(peers))
+
+		for i, p := range peers {
+			wg.Add(1)
+			go func(i int, p Peer) {
+				defer wg.Done()
+				hosts[i] = pollPeer(p)
+			}(i, p)
+		}
+		wg.Wait()
+
+		fleet := &FleetStats{Hosts: hosts}
+
+		fleetMutex.Lock()
+		cachedFleet = fleet
+		fleetMutex.Unlock()
+
+		fHub.publish(fleet)
+
+		time.Sleep(config.UpdateInterval)
+	}
+}
+
+func getCachedFleet() *FleetStats {
+	fleetMutex.RLock()
+	defer fleetMutex.RUnlock()
+	return cachedFleet
+}
+
+func handleFleet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	fleet := getCachedFleet()
+	if fleet == nil {
+		http.Error(w, "Fleet stats not yet available", http.StatusServiceUnavailable)
+		return
+	}
+
+	json.NewEncoder(w).Encode(fleet)
+}
+
+func handleFleetWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("Fleet WebSocket upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	sub := fHub.subscribe()
+	defer fHub.unsubscribe(sub)
+
+	log.Printf("Fleet client connected")
+
+	ticker := time.NewTicker(config.UpdateInterval)
+	defer ticker.Stop()
+
+	latest := getCachedFleet()
+	for {
+		select {
+		case f := <-sub:
+			latest = f
+		case <-ticker.C:
+			if latest == nil {
+				continue
+			}
+			if err := conn.WriteJSON(latest); err != nil {
+				log.Printf("Fleet WebSocket write failed: %v", err)
+				log.Printf("Fleet client disconnected")
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"runtime"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// LoadStats is the classic 1/5/15 minute load average, zeroed on Windows where
+// gopsutil has nothing meaningful to report.
+type LoadStats struct {
+	Load1  float64 `json:"load1"`
+	Load5  float64 `json:"load5"`
+	Load15 float64 `json:"load15"`
+}
+
+// SwapStats mirrors MemStats but for swap space.
+type SwapStats struct {
+	Total       uint64  `json:"total"`
+	Used        uint64  `json:"used"`
+	Free        uint64  `json:"free"`
+	UsedPercent float64 `json:"usedPercent"`
+}
+
+// TempSensor is one reading from host.SensorsTemperatures, filtered to the
+// handful of sensor keys that actually report CPU/motherboard temperature.
+type TempSensor struct {
+	SensorKey   string  `json:"sensorKey"`
+	Temperature float64 `json:"temperature"`
+}
+
+var relevantSensorPrefixes = []string{"coretemp", "k10temp", "acpi"}
+
+func getLoadStats() LoadStats {
+	if runtime.GOOS == "windows" {
+		return LoadStats{}
+	}
+	avg, err := load.Avg()
+	if err != nil {
+		return LoadStats{}
+	}
+	return LoadStats{Load1: avg.Load1, Load5: avg.Load5, Load15: avg.Load15}
+}
+
+func getSwapStats() SwapStats {
+	swap, err := mem.SwapMemory()
+	if err != nil {
+		return SwapStats{}
+	}
+	return SwapStats{
+		Total:       swap.Total,
+		Used:        swap.Used,
+		Free:        swap.Free,
+		UsedPercent: swap.UsedPercent,
+	}
+}
+
+// getTempSensors reads every sensor host.SensorsTemperatures finds and keeps
+// only the ones that are actually CPU/motherboard temperatures.
+func getTempSensors() []TempSensor {
+	sensors, err := host.SensorsTemperatures()
+	if err != nil {
+		return nil
+	}
+
+	var out []TempSensor
+	for _, s := range sensors {
+		key := strings.ToLower(s.SensorKey)
+		for _, prefix := range relevantSensorPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				out = append(out, TempSensor{SensorKey: s.SensorKey, Temperature: s.Temperature})
+				break
+			}
+		}
+	}
+	return out
+}